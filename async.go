@@ -0,0 +1,118 @@
+package rotatelogs
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrQueueFull is returned by Write when WithAsync is in effect, the
+// queue has reached its configured byte limit, and the configured
+// AsyncOverflowPolicy doesn't block.
+var ErrQueueFull = errors.New("rotatelogs: async write queue is full")
+
+// AsyncOverflowPolicy controls what Write does when the async queue
+// configured via WithAsync is full. The zero value rejects immediately;
+// see AsyncOverflowBlock for a blocking alternative.
+type AsyncOverflowPolicy struct {
+	block    bool
+	deadline time.Duration
+}
+
+// AsyncOverflowReject is the default policy: Write returns ErrQueueFull
+// immediately instead of waiting for room in the queue.
+var AsyncOverflowReject = AsyncOverflowPolicy{}
+
+// AsyncOverflowBlock returns a policy that makes Write block until room
+// is available in the queue, for up to deadline. A deadline of zero or
+// less blocks indefinitely.
+func AsyncOverflowBlock(deadline time.Duration) AsyncOverflowPolicy {
+	return AsyncOverflowPolicy{block: true, deadline: deadline}
+}
+
+// enqueue appends p to the async write queue, blocking or rejecting
+// according to rl.asyncOverflow once the queue reaches asyncQueueMax
+// bytes. The buffer is copied, since callers are free to reuse p once
+// Write returns.
+func (rl *RotateLogs) enqueue(p []byte) (int, error) {
+	buf := make([]byte, len(p))
+	copy(buf, p)
+
+	rl.asyncMu.Lock()
+	defer rl.asyncMu.Unlock()
+
+	var deadlineAt time.Time
+	if rl.asyncOverflow.block && rl.asyncOverflow.deadline > 0 {
+		deadlineAt = time.Now().Add(rl.asyncOverflow.deadline)
+	}
+
+	for rl.asyncQueueMax > 0 && rl.asyncBytes+int64(len(buf)) > rl.asyncQueueMax {
+		if !rl.asyncOverflow.block {
+			return 0, ErrQueueFull
+		}
+		if !deadlineAt.IsZero() {
+			if time.Now().After(deadlineAt) {
+				return 0, ErrQueueFull
+			}
+			timer := time.AfterFunc(10*time.Millisecond, rl.asyncCond.Broadcast)
+			rl.asyncCond.Wait()
+			timer.Stop()
+			continue
+		}
+		rl.asyncCond.Wait()
+	}
+
+	rl.asyncQueue = append(rl.asyncQueue, buf)
+	rl.asyncBytes += int64(len(buf))
+	rl.asyncCond.Broadcast()
+
+	return len(p), nil
+}
+
+// asyncLoop drains the async write queue in the background, handing each
+// buffer to writeSync in order. It exits once asyncClosed is set and the
+// queue has been fully drained.
+func (rl *RotateLogs) asyncLoop() {
+	defer rl.asyncWG.Done()
+
+	for {
+		rl.asyncMu.Lock()
+		for len(rl.asyncQueue) == 0 && !rl.asyncClosed {
+			rl.asyncCond.Wait()
+		}
+		if len(rl.asyncQueue) == 0 && rl.asyncClosed {
+			rl.asyncMu.Unlock()
+			return
+		}
+		buf := rl.asyncQueue[0]
+		rl.asyncQueue = rl.asyncQueue[1:]
+		rl.asyncBytes -= int64(len(buf))
+		rl.asyncInFlight++
+		rl.asyncCond.Broadcast()
+		rl.asyncMu.Unlock()
+
+		// writeSync already emits WriteError on failure; don't double-report it.
+		_, _ = rl.writeSync(buf)
+
+		rl.asyncMu.Lock()
+		rl.asyncInFlight--
+		rl.asyncCond.Broadcast()
+		rl.asyncMu.Unlock()
+	}
+}
+
+// Flush blocks until every buffer enqueued by a prior Write has been
+// written out, including one that's already been dequeued and is in the
+// middle of being applied to the file. It's a no-op unless WithAsync is
+// in effect.
+func (rl *RotateLogs) Flush() error {
+	if !rl.async {
+		return nil
+	}
+
+	rl.asyncMu.Lock()
+	defer rl.asyncMu.Unlock()
+	for len(rl.asyncQueue) > 0 || rl.asyncInFlight > 0 {
+		rl.asyncCond.Wait()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package rotatelogs
+
+import "time"
+
+// Clock is the interface used by RotateLogs to determine the current
+// time. It's abstracted out so that tests can inject a deterministic
+// clock instead of relying on the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+type clockFn func() time.Time
+
+func (f clockFn) Now() time.Time {
+	return f()
+}
+
+// UTC is a Clock that returns the current time in UTC.
+var UTC = clockFn(func() time.Time { return time.Now().UTC() })
+
+// Local is a Clock that returns the current time in the local timezone.
+var Local = clockFn(time.Now)
+
+type staticClock struct {
+	t time.Time
+}
+
+// NewClock creates a Clock that always returns t, regardless of when
+// Now() is called. It exists mainly so tests can freeze time without
+// reaching into RotateLogs internals.
+func NewClock(t time.Time) Clock {
+	return &staticClock{t: t}
+}
+
+func (c *staticClock) Now() time.Time {
+	return c.t
+}
@@ -0,0 +1,66 @@
+package rotatelogs
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// scheduleCompress gzips path in the background and removes the
+// original once compression succeeds, so Write is never blocked on
+// disk-heavy compression work.
+func (rl *RotateLogs) scheduleCompress(path string) {
+	rl.compressWG.Add(1)
+	go func() {
+		defer rl.compressWG.Done()
+		if err := compressFile(rl.fs, path, rl.compressLevel); err != nil {
+			rl.events.emit(WriteError{Err: err})
+			return
+		}
+		rl.events.emit(CompressCompleted{Path: path + ".gz"})
+	}()
+}
+
+// compressFile gzips path to path+".gz" on fs and removes path on success.
+func compressFile(fs FS, path string, level int) error {
+	src, err := fs.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s for compression: %w", path, err)
+	}
+	defer src.Close()
+
+	dstName := path + ".gz"
+	dst, err := fs.OpenFile(dstName, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dstName, err)
+	}
+
+	gw, err := gzip.NewWriterLevel(dst, level)
+	if err != nil {
+		dst.Close()
+		fs.Remove(dstName)
+		return fmt.Errorf("failed to create gzip writer for %s: %w", dstName, err)
+	}
+
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		dst.Close()
+		fs.Remove(dstName)
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		fs.Remove(dstName)
+		return fmt.Errorf("failed to finalize gzip stream for %s: %w", dstName, err)
+	}
+	if err := dst.Close(); err != nil {
+		fs.Remove(dstName)
+		return fmt.Errorf("failed to close %s: %w", dstName, err)
+	}
+
+	if err := fs.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove compressed source %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package rotatelogs
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event is implemented by every event type RotateLogs emits to
+// subscribers registered via Subscribe.
+type Event interface {
+	Type() string
+}
+
+// FileRotated is emitted whenever RotateLogs starts writing to a new
+// file, whether the rotation was triggered by time, size, or an
+// explicit call to Rotate. Prev is empty for the very first file a
+// RotateLogs instance opens.
+type FileRotated struct {
+	Prev string
+	Next string
+	Time time.Time
+}
+
+// Type implements Event.
+func (FileRotated) Type() string { return "FileRotated" }
+
+// FilePurged is emitted for each file removed by maxAge or
+// rotationCount purging.
+type FilePurged struct {
+	Path string
+}
+
+// Type implements Event.
+func (FilePurged) Type() string { return "FilePurged" }
+
+// CompressCompleted is emitted once a rotated file has finished being
+// gzipped by WithCompress. Path is the resulting ".gz" file.
+type CompressCompleted struct {
+	Path string
+}
+
+// Type implements Event.
+func (CompressCompleted) Type() string { return "CompressCompleted" }
+
+// WriteError is emitted whenever Write fails, whether because rotation
+// couldn't acquire a writable file or the underlying write itself
+// failed.
+type WriteError struct {
+	Err error
+}
+
+// Type implements Event.
+func (WriteError) Type() string { return "WriteError" }
+
+// eventBus fans out events to subscribers without ever blocking the
+// caller: a subscriber whose channel is full simply misses the event,
+// and the miss is tallied in Dropped.
+type eventBus struct {
+	mu      sync.Mutex
+	nextID  int
+	subs    map[int]chan<- Event
+	dropped uint64
+}
+
+// Subscribe registers ch to receive every Event RotateLogs emits from
+// then on. The returned func removes the subscription; calling it more
+// than once is a no-op. Delivery is non-blocking: if ch isn't ready to
+// receive, the event is dropped and counted in DroppedEvents.
+func (rl *RotateLogs) Subscribe(ch chan<- Event) (unsubscribe func()) {
+	return rl.events.subscribe(ch)
+}
+
+// DroppedEvents returns the number of events that were dropped so far
+// because a subscriber's channel was full.
+func (rl *RotateLogs) DroppedEvents() uint64 {
+	return atomic.LoadUint64(&rl.events.dropped)
+}
+
+func (b *eventBus) subscribe(ch chan<- Event) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = make(map[int]chan<- Event)
+	}
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = ch
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+		})
+	}
+}
+
+func (b *eventBus) emit(ev Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+			atomic.AddUint64(&b.dropped, 1)
+		}
+	}
+}
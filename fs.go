@@ -0,0 +1,38 @@
+package rotatelogs
+
+import (
+	"io"
+	"os"
+)
+
+// File is the subset of *os.File that RotateLogs needs from whatever
+// FS backs it.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Closer
+	Stat() (os.FileInfo, error)
+}
+
+// FS abstracts the filesystem operations RotateLogs performs, so that
+// tests can swap in an in-memory implementation (see NewMemFS) and
+// downstream users can plug in remote or memory-mapped backends for
+// log shipping without touching the rotation logic itself.
+type FS interface {
+	Open(name string) (File, error)
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	Rename(oldpath, newpath string) error
+	Remove(name string) error
+	Symlink(oldname, newname string) error
+	Readlink(name string) (string, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Glob(pattern string) ([]string, error)
+}
+
+// fileExists reports whether path exists on fs, regardless of type.
+func fileExists(fs FS, path string) bool {
+	_, err := fs.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,222 @@
+package rotatelogs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewMemFS returns an in-memory FS, primarily meant for tests that want
+// to exercise RotateLogs's rotation logic without touching disk. File
+// mtimes are stamped from the real wall clock; use NewMemFSWithClock to
+// pair it with the same fake Clock passed to WithClock, which is
+// required for tests that exercise WithMaxAge-based purging.
+// Directories are implicit: MkdirAll is a no-op and any path can be
+// opened as soon as its parent "exists" in spirit.
+func NewMemFS() FS {
+	return NewMemFSWithClock(Local)
+}
+
+// NewMemFSWithClock returns an in-memory FS whose file mtimes are
+// stamped using clock instead of the wall clock. Pair it with the same
+// Clock passed to WithClock so that age-based purging (WithMaxAge) sees
+// mtimes on the same timeline as rl.clock.Now(); otherwise the purge
+// cutoff and the files' mtimes are computed from unrelated clocks and
+// purge decisions are meaningless.
+func NewMemFSWithClock(clock Clock) FS {
+	return &memFS{entries: make(map[string]*memEntry), clock: clock}
+}
+
+type memEntry struct {
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+	link    string // target, non-empty for symlinks
+}
+
+type memFS struct {
+	mu      sync.Mutex
+	entries map[string]*memEntry
+	clock   Clock
+}
+
+func (fs *memFS) Open(name string) (File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (fs *memFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	e, ok := fs.entries[name]
+	if !ok {
+		if flag&os.O_CREATE == 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		e = &memEntry{mode: perm, modTime: fs.clock.Now()}
+		fs.entries[name] = e
+	} else if flag&os.O_TRUNC != 0 {
+		e.data = nil
+	}
+
+	offset := 0
+	if flag&os.O_APPEND != 0 {
+		offset = len(e.data)
+	}
+
+	return &memFile{
+		fs:       fs,
+		name:     name,
+		entry:    e,
+		offset:   offset,
+		writable: flag&(os.O_WRONLY|os.O_RDWR) != 0,
+	}, nil
+}
+
+func (fs *memFS) Stat(name string) (os.FileInfo, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	e, ok := fs.entries[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return e.fileInfo(name), nil
+}
+
+func (fs *memFS) Lstat(name string) (os.FileInfo, error) {
+	return fs.Stat(name)
+}
+
+func (fs *memFS) Rename(oldpath, newpath string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	e, ok := fs.entries[oldpath]
+	if !ok {
+		return &os.PathError{Op: "rename", Path: oldpath, Err: os.ErrNotExist}
+	}
+	fs.entries[newpath] = e
+	delete(fs.entries, oldpath)
+	return nil
+}
+
+func (fs *memFS) Remove(name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, ok := fs.entries[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(fs.entries, name)
+	return nil
+}
+
+func (fs *memFS) Symlink(oldname, newname string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.entries[newname] = &memEntry{link: oldname, mode: os.ModeSymlink, modTime: fs.clock.Now()}
+	return nil
+}
+
+func (fs *memFS) Readlink(name string) (string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	e, ok := fs.entries[name]
+	if !ok || e.link == "" {
+		return "", &os.PathError{Op: "readlink", Path: name, Err: os.ErrInvalid}
+	}
+	return e.link, nil
+}
+
+func (fs *memFS) MkdirAll(path string, perm os.FileMode) error {
+	return nil
+}
+
+func (fs *memFS) Glob(pattern string) ([]string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	var matches []string
+	for name := range fs.entries {
+		ok, err := filepath.Match(pattern, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func (e *memEntry) fileInfo(name string) os.FileInfo {
+	return &memFileInfo{
+		name:    filepath.Base(name),
+		size:    int64(len(e.data)),
+		mode:    e.mode,
+		modTime: e.modTime,
+	}
+}
+
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (fi *memFileInfo) Name() string       { return fi.name }
+func (fi *memFileInfo) Size() int64        { return fi.size }
+func (fi *memFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *memFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *memFileInfo) IsDir() bool        { return false }
+func (fi *memFileInfo) Sys() interface{}   { return nil }
+
+// memFile is the File handle returned by memFS.
+type memFile struct {
+	fs       *memFS
+	name     string
+	entry    *memEntry
+	offset   int
+	writable bool
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	if !f.writable {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: os.ErrPermission}
+	}
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if f.offset > len(f.entry.data) {
+		f.entry.data = append(f.entry.data, make([]byte, f.offset-len(f.entry.data))...)
+	}
+	f.entry.data = append(f.entry.data[:f.offset], p...)
+	f.offset += len(p)
+	f.entry.modTime = f.fs.clock.Now()
+	return len(p), nil
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+
+	if f.offset >= len(f.entry.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.entry.data[f.offset:])
+	f.offset += n
+	return n, nil
+}
+
+func (f *memFile) Close() error {
+	return nil
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.entry.fileInfo(f.name), nil
+}
@@ -0,0 +1,137 @@
+package rotatelogs
+
+import "time"
+
+// Option configures a RotateLogs instance. Options are applied in the
+// order they're passed to New.
+type Option func(*RotateLogs)
+
+// WithClock sets the Clock used to determine "now" when generating
+// filenames and deciding when to rotate. Defaults to Local.
+func WithClock(c Clock) Option {
+	return func(rl *RotateLogs) {
+		rl.clock = c
+	}
+}
+
+// WithLocation is a shorthand for WithClock, using the current local
+// time translated to the given location.
+func WithLocation(loc *time.Location) Option {
+	return func(rl *RotateLogs) {
+		rl.clock = clockFn(func() time.Time { return time.Now().In(loc) })
+	}
+}
+
+// WithLinkName sets the symbolic link name that's updated to always
+// point at the current log file after every rotation.
+func WithLinkName(s string) Option {
+	return func(rl *RotateLogs) {
+		rl.linkName = s
+	}
+}
+
+// WithMaxAge sets the duration after which old log files are purged.
+// A zero or negative duration disables age-based purging.
+func WithMaxAge(d time.Duration) Option {
+	return func(rl *RotateLogs) {
+		if d < 0 {
+			d = 0
+		}
+		rl.maxAge = d
+	}
+}
+
+// WithRotationTime sets how often the log file is rotated by time.
+// A zero or negative duration disables time-based rotation.
+func WithRotationTime(d time.Duration) Option {
+	return func(rl *RotateLogs) {
+		if d < 0 {
+			d = 0
+		}
+		rl.rotationTime = d
+	}
+}
+
+// WithRotationCount sets the number of rotated log files to keep.
+// A zero value disables count-based purging.
+func WithRotationCount(n int) Option {
+	return func(rl *RotateLogs) {
+		if n < 0 {
+			n = 0
+		}
+		rl.rotationCount = n
+	}
+}
+
+// WithRotationSize sets the number of bytes after which the log file is
+// rotated by size, following lumberjack's MaxSize model. A zero or
+// negative value disables size-based rotation.
+func WithRotationSize(bytes int64) Option {
+	return func(rl *RotateLogs) {
+		if bytes < 0 {
+			bytes = 0
+		}
+		rl.rotationSize = bytes
+	}
+}
+
+// WithFS overrides the filesystem RotateLogs reads from and writes to.
+// Defaults to the real filesystem; see NewMemFS for an in-memory
+// implementation useful in tests.
+func WithFS(fs FS) Option {
+	return func(rl *RotateLogs) {
+		rl.fs = fs
+	}
+}
+
+// WithCompress gzips each file once it's rotated away from, removing
+// the uncompressed original. Compression runs in the background so it
+// doesn't add latency to Write.
+func WithCompress(b bool) Option {
+	return func(rl *RotateLogs) {
+		rl.compress = b
+	}
+}
+
+// WithCompressLevel sets the gzip compression level used by
+// WithCompress. Accepts any level documented by compress/gzip, e.g.
+// gzip.BestSpeed or gzip.BestCompression. Defaults to
+// gzip.DefaultCompression.
+func WithCompressLevel(level int) Option {
+	return func(rl *RotateLogs) {
+		rl.compressLevel = level
+	}
+}
+
+// WithAsync makes Write enqueue onto a bounded in-memory queue instead of
+// writing synchronously, returning as soon as the buffer is queued; a
+// background goroutine drains the queue and performs the actual writes.
+// queueBytes caps the queue's total size; once full, Write's behavior is
+// governed by WithAsyncOverflowPolicy (by default, ErrQueueFull). Use
+// Flush to wait for the queue to drain; Close flushes automatically.
+func WithAsync(queueBytes int64) Option {
+	return func(rl *RotateLogs) {
+		rl.async = true
+		if queueBytes < 0 {
+			queueBytes = 0
+		}
+		rl.asyncQueueMax = queueBytes
+	}
+}
+
+// WithAsyncOverflowPolicy sets the behavior of Write when WithAsync is in
+// effect and the queue is full. Defaults to AsyncOverflowReject.
+func WithAsyncOverflowPolicy(p AsyncOverflowPolicy) Option {
+	return func(rl *RotateLogs) {
+		rl.asyncOverflow = p
+	}
+}
+
+// ForceNewFile forces New to create a new, uniquely suffixed file every
+// time it's called, even when a file already exists for the current
+// pattern/time combination.
+func ForceNewFile() Option {
+	return func(rl *RotateLogs) {
+		rl.forceNewFile = true
+	}
+}
@@ -0,0 +1,402 @@
+// Package rotatelogs provides an io.Writer that writes to the file
+// named in its pattern, rotating to a new file as the pattern dictates
+// (usually because it embeds a timestamp verb that has changed) or as
+// explicitly requested via Rotate.
+package rotatelogs
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateLogs represents a log file that gets automatically rotated as
+// you write to it.
+type RotateLogs struct {
+	pattern       string
+	globPattern   string
+	clock         Clock
+	linkName      string
+	maxAge        time.Duration
+	rotationTime  time.Duration
+	rotationCount int
+	rotationSize  int64
+	forceNewFile  bool
+	compress      bool
+	compressLevel int
+	async         bool
+	asyncQueueMax int64
+	asyncOverflow AsyncOverflowPolicy
+
+	fs     FS
+	events eventBus
+
+	mutex      sync.Mutex
+	curFn      string
+	curBaseFn  string
+	generation int
+	curFile    File
+	curSize    int64
+	compressWG sync.WaitGroup
+
+	asyncMu       sync.Mutex
+	asyncCond     *sync.Cond
+	asyncQueue    [][]byte
+	asyncBytes    int64
+	asyncInFlight int
+	asyncClosed   bool
+	asyncWG       sync.WaitGroup
+}
+
+// New creates a new RotateLogs that writes to files matching the given
+// strftime-style pattern. Use the With* options to customize rotation
+// behavior; by default files are rotated once a day and never purged.
+func New(p string, options ...Option) (*RotateLogs, error) {
+	rl := &RotateLogs{
+		pattern:       p,
+		globPattern:   globPatternFromPattern(p),
+		clock:         Local,
+		rotationTime:  24 * time.Hour,
+		compressLevel: gzip.DefaultCompression,
+		fs:            osFS{},
+	}
+
+	for _, o := range options {
+		o(rl)
+	}
+
+	if rl.maxAge > 0 && rl.rotationCount > 0 {
+		return nil, errors.New("rotatelogs: options MaxAge and RotationCount cannot be used together")
+	}
+
+	if rl.async {
+		rl.asyncCond = sync.NewCond(&rl.asyncMu)
+		rl.asyncWG.Add(1)
+		go rl.asyncLoop()
+	}
+
+	return rl, nil
+}
+
+// genFilename computes the base filename for the current point in time,
+// truncated down to a boundary of rl.rotationTime since local midnight.
+// With the default 24h rotation time this yields one filename per day;
+// a shorter rotation time yields correspondingly finer-grained names.
+func (rl *RotateLogs) genFilename() string {
+	now := rl.clock.Now()
+	if rl.rotationTime > 0 {
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		elapsed := now.Sub(midnight)
+		now = midnight.Add(elapsed - elapsed%rl.rotationTime)
+	}
+	return genFilename(rl.pattern, now)
+}
+
+// getWritableFile returns the *os.File that Write should use, opening
+// or rotating to a new one as needed. When the pattern for "now" hasn't
+// changed since the last call and forceNewFile is false, the
+// already-open file is reused. Otherwise a new generation is opened:
+// the first file for a given base name is created bare, and subsequent
+// ones for the same base name get a ".N" suffix, mirroring Rotate.
+func (rl *RotateLogs) getWritableFile(forceNewFile bool) (File, error) {
+	baseFn := rl.genFilename()
+	generation := rl.generation
+
+	switch {
+	case baseFn != rl.curBaseFn:
+		generation = 0
+	case rl.curFile != nil && !forceNewFile:
+		return rl.curFile, nil
+	default:
+		generation++
+	}
+
+	filename := baseFn
+	if generation > 0 {
+		filename = fmt.Sprintf("%s.%d", baseFn, generation)
+	}
+
+	if forceNewFile {
+		for fileExists(rl.fs, filename) {
+			generation++
+			filename = fmt.Sprintf("%s.%d", baseFn, generation)
+		}
+	}
+
+	fh, err := rl.openFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	prevFn := rl.curFn
+	if rl.curFile != nil {
+		_ = rl.curFile.Close()
+		if rl.compress && prevFn != "" {
+			rl.scheduleCompress(prevFn)
+		}
+	}
+
+	rl.curFile = fh
+	rl.curFn = filename
+	rl.curBaseFn = baseFn
+	rl.generation = generation
+
+	if err := rl.updateSymlink(filename); err != nil {
+		return nil, err
+	}
+
+	rl.purge()
+
+	rl.events.emit(FileRotated{Prev: prevFn, Next: filename, Time: rl.clock.Now()})
+
+	return fh, nil
+}
+
+// openFile creates (or opens, in append mode) filename and seeds
+// rl.curSize from its current size, so that size-based rotation keeps
+// working across process restarts where the writer picks back up an
+// existing file.
+func (rl *RotateLogs) openFile(filename string) (File, error) {
+	if err := rl.fs.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", filepath.Dir(filename), err)
+	}
+
+	fh, err := rl.fs.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+
+	var size int64
+	if fi, err := fh.Stat(); err == nil {
+		size = fi.Size()
+	}
+	rl.curSize = size
+
+	return fh, nil
+}
+
+// updateSymlink atomically repoints linkName at filename, computing the
+// relative path between them so the link keeps working if the directory
+// tree is moved.
+func (rl *RotateLogs) updateSymlink(filename string) error {
+	if rl.linkName == "" {
+		return nil
+	}
+
+	linkDir := filepath.Dir(rl.linkName)
+	if err := rl.fs.MkdirAll(linkDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory for symlink %s: %w", rl.linkName, err)
+	}
+
+	absLinkDir, err := filepath.Abs(linkDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve symlink directory %s: %w", linkDir, err)
+	}
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return fmt.Errorf("failed to resolve log file path %s: %w", filename, err)
+	}
+
+	linkDest, err := filepath.Rel(absLinkDir, absFilename)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate relative path for symlink %s: %w", rl.linkName, err)
+	}
+
+	tmpLink := rl.linkName + `_symlink`
+	if err := rl.fs.Remove(tmpLink); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale symlink %s: %w", tmpLink, err)
+	}
+	if err := rl.fs.Symlink(linkDest, tmpLink); err != nil {
+		return fmt.Errorf("failed to create new symlink %s: %w", tmpLink, err)
+	}
+	if err := rl.fs.Rename(tmpLink, rl.linkName); err != nil {
+		return fmt.Errorf("failed to rename new symlink to %s: %w", rl.linkName, err)
+	}
+	return nil
+}
+
+// logicalRotation groups together the plain and gzip-compressed forms
+// of a single rotated file, so maxAge/rotationCount purging counts and
+// deletes them as one unit regardless of which form happens to be on
+// disk at the moment (compression runs asynchronously, so both can
+// briefly coexist).
+type logicalRotation struct {
+	key   string
+	paths []string
+	mod   time.Time
+}
+
+func (rl *RotateLogs) logicalRotations() []logicalRotation {
+	matches, err := rl.fs.Glob(rl.globPattern)
+	if err != nil {
+		matches = nil
+	}
+	if rl.compress {
+		if gzMatches, err := rl.fs.Glob(rl.globPattern + ".gz"); err == nil {
+			matches = append(matches, gzMatches...)
+		}
+	}
+
+	byKey := make(map[string]*logicalRotation)
+	var order []string
+	for _, path := range matches {
+		key := strings.TrimSuffix(path, ".gz")
+		lr, ok := byKey[key]
+		if !ok {
+			lr = &logicalRotation{key: key}
+			byKey[key] = lr
+			order = append(order, key)
+		}
+		lr.paths = append(lr.paths, path)
+		if fi, err := rl.fs.Stat(path); err == nil && fi.ModTime().After(lr.mod) {
+			lr.mod = fi.ModTime()
+		}
+	}
+
+	rotations := make([]logicalRotation, 0, len(order))
+	for _, key := range order {
+		rotations = append(rotations, *byKey[key])
+	}
+	return rotations
+}
+
+// purge removes old rotated files once a new file has been opened,
+// honoring whichever of maxAge/rotationCount is configured. The two are
+// mutually exclusive, enforced in New.
+func (rl *RotateLogs) purge() {
+	if rl.maxAge <= 0 && rl.rotationCount <= 0 {
+		return
+	}
+
+	rotations := rl.logicalRotations()
+
+	var toPurge []logicalRotation
+	switch {
+	case rl.maxAge > 0:
+		cutoff := rl.clock.Now().Add(-rl.maxAge)
+		for _, lr := range rotations {
+			if lr.mod.After(cutoff) {
+				continue
+			}
+			toPurge = append(toPurge, lr)
+		}
+	case rl.rotationCount > 0:
+		if len(rotations) <= rl.rotationCount {
+			return
+		}
+		sort.Slice(rotations, func(i, j int) bool { return rotations[i].mod.Before(rotations[j].mod) })
+		toPurge = rotations[:len(rotations)-rl.rotationCount]
+	}
+
+	for _, lr := range toPurge {
+		if lr.key == rl.curFn {
+			continue
+		}
+		for _, path := range lr.paths {
+			if err := rl.fs.Remove(path); err == nil {
+				rl.events.emit(FilePurged{Path: path})
+			}
+		}
+	}
+}
+
+// Write satisfies the io.Writer interface. With WithAsync it enqueues p
+// for a background goroutine to write and returns immediately; otherwise
+// it writes synchronously, as described on writeSync.
+func (rl *RotateLogs) Write(p []byte) (int, error) {
+	if rl.async {
+		return rl.enqueue(p)
+	}
+	return rl.writeSync(p)
+}
+
+// writeSync rotates to a new file first if the current time has crossed
+// into a new rotation period, and again mid-write if WithRotationSize
+// would otherwise be exceeded; whichever trigger fires first wins.
+func (rl *RotateLogs) writeSync(p []byte) (int, error) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	// ForceNewFile only needs to steer clear of clobbering an existing
+	// file the first time this instance opens one; once a file is open,
+	// subsequent writes should reuse it like any other writer.
+	force := rl.forceNewFile && rl.curFile == nil
+	prevFn := rl.curFn
+	out, err := rl.getWritableFile(force)
+	if err != nil {
+		err = fmt.Errorf("failed to acquire target io.Writer: %w", err)
+		rl.events.emit(WriteError{Err: err})
+		return 0, err
+	}
+
+	// If the call above just opened a brand new file (time rotation, the
+	// very first open, or ForceNewFile), there's nothing written to it
+	// yet for the size check to meaningfully act on: forcing another
+	// rotation here would leave that file permanently empty and fire two
+	// FileRotated events for what should be a single winning trigger.
+	freshlyRotated := rl.curFn != prevFn && rl.curSize == 0
+	if !freshlyRotated && rl.rotationSize > 0 && rl.curSize+int64(len(p)) > rl.rotationSize {
+		out, err = rl.getWritableFile(true)
+		if err != nil {
+			err = fmt.Errorf("failed to rotate for size: %w", err)
+			rl.events.emit(WriteError{Err: err})
+			return 0, err
+		}
+	}
+
+	n, err := out.Write(p)
+	rl.curSize += int64(n)
+	if err != nil {
+		rl.events.emit(WriteError{Err: err})
+	}
+	return n, err
+}
+
+// Rotate forces a rotation to a new file, regardless of whether the
+// rotation pattern or size threshold has actually been reached.
+func (rl *RotateLogs) Rotate() error {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	_, err := rl.getWritableFile(true)
+	return err
+}
+
+// CurrentFileName returns the path of the file currently being written
+// to.
+func (rl *RotateLogs) CurrentFileName() string {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	return rl.curFn
+}
+
+// Close satisfies the io.Closer interface, closing the currently open
+// file, if any, and blocking until any in-flight compression triggered
+// by WithCompress has finished.
+func (rl *RotateLogs) Close() error {
+	if rl.async {
+		rl.Flush()
+		rl.asyncMu.Lock()
+		rl.asyncClosed = true
+		rl.asyncCond.Broadcast()
+		rl.asyncMu.Unlock()
+		rl.asyncWG.Wait()
+	}
+
+	rl.mutex.Lock()
+	var err error
+	if rl.curFile != nil {
+		err = rl.curFile.Close()
+		rl.curFile = nil
+	}
+	rl.mutex.Unlock()
+
+	rl.compressWG.Wait()
+	return err
+}
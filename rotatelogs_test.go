@@ -1,12 +1,15 @@
 package rotatelogs
 
 import (
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,11 +28,22 @@ func TestSatisfiesIOCloser(t *testing.T) {
 	_ = c
 }
 
+// readAll reads the full contents of path on fs. It's the memFS
+// equivalent of os.ReadFile, used throughout the WithFS-based tests.
+func readAll(fs FS, path string) ([]byte, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
 func TestLogRotate(t *testing.T) {
 	testCases := []struct {
 		Name        string
 		FixArgs     func([]Option, string) []Option
-		CheckExtras func(*testing.T, *RotateLogs, string) bool
+		CheckExtras func(*testing.T, FS, *RotateLogs, string) bool
 	}{
 		{
 			Name: "Basic Usage",
@@ -41,10 +55,10 @@ func TestLogRotate(t *testing.T) {
 
 				return append(options, WithLinkName(linkName))
 			},
-			CheckExtras: func(t *testing.T, rl *RotateLogs, dir string) bool {
+			CheckExtras: func(t *testing.T, fs FS, rl *RotateLogs, dir string) bool {
 				linkName := filepath.Join(dir, "log")
-				linkDest, err := os.Readlink(linkName)
-				if !assert.NoError(t, err, `os.Readlink(%#v) should succeed`, linkName) {
+				linkDest, err := fs.Readlink(linkName)
+				if !assert.NoError(t, err, `fs.Readlink(%#v) should succeed`, linkName) {
 					return false
 				}
 
@@ -61,10 +75,10 @@ func TestLogRotate(t *testing.T) {
 
 				return append(options, WithLinkName(linkName))
 			},
-			CheckExtras: func(t *testing.T, rl *RotateLogs, dir string) bool {
+			CheckExtras: func(t *testing.T, fs FS, rl *RotateLogs, dir string) bool {
 				linkName := filepath.Join(dir, "nest1", "nest2", "log")
-				linkDest, err := os.Readlink(linkName)
-				if !assert.NoError(t, err, `os.Readlink(%#v) should succeed`, linkName) {
+				linkDest, err := fs.Readlink(linkName)
+				if !assert.NoError(t, err, `fs.Readlink(%#v) should succeed`, linkName) {
 					return false
 				}
 
@@ -80,17 +94,18 @@ func TestLogRotate(t *testing.T) {
 		i := i   // avoid lint errors
 		tc := tc // avoid lint errors
 		t.Run(tc.Name, func(t *testing.T) {
-			dir, err := os.MkdirTemp("", fmt.Sprintf("file-rotatelogs-test%d", i))
-			if !assert.NoError(t, err, "creating temporary directory should succeed") {
-				return
-			}
+			dir := fmt.Sprintf("/var/log/file-rotatelogs-test%d", i)
 
-			// Change current time, so we can safely purge old logs
+			// Change current time, so we can safely purge old logs. The
+			// clock is mutable so the in-memory FS and RotateLogs stay on
+			// the same timeline as we jump it forward below.
 			dummyTime := time.Now().Add(-7 * 24 * time.Hour)
 			dummyTime = dummyTime.Add(time.Duration(-1 * dummyTime.Nanosecond()))
-			clock := NewClock(dummyTime)
+			now := dummyTime
+			clock := ClockFunc(func() time.Time { return now })
+			fs := NewMemFSWithClock(clock)
 
-			options := []Option{WithClock(clock), WithMaxAge(24 * time.Hour)}
+			options := []Option{WithFS(fs), WithClock(clock), WithMaxAge(24 * time.Hour)}
 			if fn := tc.FixArgs; fn != nil {
 				options = fn(options, dir)
 			}
@@ -115,7 +130,7 @@ func TestLogRotate(t *testing.T) {
 				t.Errorf("Could not get filename %s", fn)
 			}
 
-			content, err := os.ReadFile(fn)
+			content, err := readAll(fs, fn)
 			if err != nil {
 				t.Errorf("Failed to read file %s: %s", fn, err)
 			}
@@ -124,26 +139,17 @@ func TestLogRotate(t *testing.T) {
 				t.Errorf(`File content does not match (was "%s")`, content)
 			}
 
-			err = os.Chtimes(fn, dummyTime, dummyTime)
-			if err != nil {
-				t.Errorf("Failed to change access/modification times for %s: %s", fn, err)
-			}
-
-			fi, err := os.Stat(fn)
+			fi, err := fs.Stat(fn)
 			if err != nil {
 				t.Errorf("Failed to stat %s: %s", fn, err)
 			}
 
 			if !fi.ModTime().Equal(dummyTime) {
-				t.Errorf("Failed to chtime for %s (expected %s, got %s)", fn, fi.ModTime(), dummyTime)
+				t.Errorf("Expected %s to carry the clock's mtime (expected %s, got %s)", fn, dummyTime, fi.ModTime())
 			}
 
 			assert.NoError(t, rl.Close())
-			clock = NewClock(dummyTime.Add(7 * 24 * time.Hour))
-			options = []Option{WithClock(clock), WithMaxAge(24 * time.Hour)}
-			if fn := tc.FixArgs; fn != nil {
-				options = fn(options, dir)
-			}
+			now = dummyTime.Add(7 * 24 * time.Hour)
 
 			rl, err = New(filepath.Join(dir, "log%Y%m%d%H%M%S"), options...)
 			if !assert.NoError(t, err, `New should succeed`) {
@@ -157,7 +163,7 @@ func TestLogRotate(t *testing.T) {
 				t.Errorf(`New file name and old file name should not match ("%s" != "%s")`, fn, newfn)
 			}
 
-			content, err = os.ReadFile(newfn)
+			content, err = readAll(fs, newfn)
 			if err != nil {
 				t.Errorf("Failed to read file %s: %s", newfn, err)
 			}
@@ -166,52 +172,56 @@ func TestLogRotate(t *testing.T) {
 				t.Errorf(`File content does not match (was "%s")`, content)
 			}
 
-			time.Sleep(time.Second)
-
 			// fn was declared above, before mocking CurrentTime
 			// Old files should have been unlinked
-			_, err = os.Stat(fn)
-			if !assert.Error(t, err, "os.Stat should have failed") {
+			_, err = fs.Stat(fn)
+			if !assert.Error(t, err, "fs.Stat should have failed") {
 				return
 			}
 
 			if fn := tc.CheckExtras; fn != nil {
-				if !fn(t, rl, dir) {
+				if !fn(t, fs, rl, dir) {
 					return
 				}
 			}
 
 			assert.NoError(t, rl.Close())
-			assert.NoError(t, os.RemoveAll(dir))
 		})
 	}
 }
 
-func CreateRotationTestFile(dir string, base time.Time, d time.Duration, n int) {
+// CreateRotationTestFile pre-populates fs with n rotated-looking log
+// files spaced d apart starting at base, as if a previous process had
+// already been rotating into dir. setNow moves the clock shared with fs
+// so each file's mtime lines up with its embedded timestamp.
+func CreateRotationTestFile(fs FS, setNow func(time.Time), dir string, base time.Time, d time.Duration, n int) {
 	timestamp := base
 	for i := 0; i < n; i++ {
 		// %Y%m%d%H%M%S
 		suffix := timestamp.Format("20060102150405")
 		path := filepath.Join(dir, "log"+suffix)
-		_ = os.WriteFile(path, []byte("rotation test file\n"), os.ModePerm)
-		_ = os.Chtimes(path, timestamp, timestamp)
+		setNow(timestamp)
+		if f, err := fs.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm); err == nil {
+			_, _ = f.Write([]byte("rotation test file\n"))
+			_ = f.Close()
+		}
 		timestamp = timestamp.Add(d)
 	}
 }
 
 func TestLogRotationCount(t *testing.T) {
-	dir, err := os.MkdirTemp("", "file-rotatelogs-rotationcount-test")
-	if !assert.NoError(t, err, "creating temporary directory should succeed") {
-		return
-	}
+	dir := "/var/log/file-rotatelogs-rotationcount-test"
 
 	dummyTime := time.Now().Add(-7 * 24 * time.Hour)
 	dummyTime = dummyTime.Add(time.Duration(-1 * dummyTime.Nanosecond()))
-	clock := NewClock(dummyTime)
+	now := dummyTime
+	clock := ClockFunc(func() time.Time { return now })
+	fs := NewMemFSWithClock(clock)
 
 	t.Run("Either maxAge or rotationCount should be set", func(t *testing.T) {
 		rl, err := New(
 			filepath.Join(dir, "log%Y%m%d%H%M%S"),
+			WithFS(fs),
 			WithClock(clock),
 			WithMaxAge(time.Duration(0)),
 			WithRotationCount(0),
@@ -225,6 +235,7 @@ func TestLogRotationCount(t *testing.T) {
 	t.Run("Either maxAge or rotationCount should be set", func(t *testing.T) {
 		_, err := New(
 			filepath.Join(dir, "log%Y%m%d%H%M%S"),
+			WithFS(fs),
 			WithClock(clock),
 			WithMaxAge(1),
 			WithRotationCount(1),
@@ -235,6 +246,7 @@ func TestLogRotationCount(t *testing.T) {
 	t.Run("Only latest log file is kept", func(t *testing.T) {
 		rl, err := New(
 			filepath.Join(dir, "log%Y%m%d%H%M%S"),
+			WithFS(fs),
 			WithClock(clock),
 			WithMaxAge(-1),
 			WithRotationCount(1),
@@ -250,8 +262,7 @@ func TestLogRotationCount(t *testing.T) {
 		if !assert.Len(t, "dummy", n, "rl.Write should succeed") {
 			return
 		}
-		time.Sleep(time.Second)
-		files, _ := filepath.Glob(filepath.Join(dir, "log*"))
+		files, _ := fs.Glob(filepath.Join(dir, "log*"))
 		if !assert.Equal(t, 1, len(files), "Only latest log is kept") {
 			return
 		}
@@ -259,9 +270,17 @@ func TestLogRotationCount(t *testing.T) {
 	})
 
 	t.Run("Old log files are purged except 2 log files", func(t *testing.T) {
-		CreateRotationTestFile(dir, dummyTime, time.Hour, 5)
+		CreateRotationTestFile(fs, func(t time.Time) { now = t }, dir, dummyTime, time.Hour, 5)
+
+		// Advance past the pre-populated files before writing, so the
+		// live file's mtime is unambiguously the newest of the bunch
+		// even though its midnight-truncated filename sorts before them
+		// alphabetically - purge must sort by real mtime, not filename.
+		now = dummyTime.Add(5 * time.Hour)
+
 		rl, err := New(
 			filepath.Join(dir, "log%Y%m%d%H%M%S"),
+			WithFS(fs),
 			WithClock(clock),
 			WithMaxAge(-1),
 			WithRotationCount(2),
@@ -277,15 +296,12 @@ func TestLogRotationCount(t *testing.T) {
 		if !assert.Len(t, "dummy", n, "rl.Write should succeed") {
 			return
 		}
-		time.Sleep(time.Second)
-		files, _ := filepath.Glob(filepath.Join(dir, "log*"))
+		files, _ := fs.Glob(filepath.Join(dir, "log*"))
 		if !assert.Equal(t, 2, len(files), "One file is kept") {
 			return
 		}
 		assert.NoError(t, rl.Close())
 	})
-
-	assert.NoError(t, os.RemoveAll(dir))
 }
 
 func TestLogSetOutput(t *testing.T) {
@@ -437,6 +453,291 @@ func TestRotationGenerationalNames(t *testing.T) {
 	assert.NoError(t, os.RemoveAll(dir))
 }
 
+func TestRotationBySize(t *testing.T) {
+	dir, err := os.MkdirTemp("", "file-rotatelogs-size")
+	if !assert.NoError(t, err, `creating temporary directory should succeed`) {
+		return
+	}
+
+	rl, err := New(
+		filepath.Join(dir, "size.log"),
+		WithRotationSize(10),
+	)
+	if !assert.NoError(t, err, `New should succeed`) {
+		return
+	}
+
+	for i := 0; i < 5; i++ {
+		_, err := rl.Write([]byte("0123456789"))
+		if !assert.NoError(t, err, "rl.Write should succeed") {
+			return
+		}
+	}
+	assert.NoError(t, rl.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "size.log*"))
+	if !assert.NoError(t, err, "filepath.Glob should succeed") {
+		return
+	}
+	if !assert.True(t, len(files) > 1, "writing past the size threshold should produce more than one file") {
+		return
+	}
+
+	for _, fn := range files {
+		assert.True(t, strings.HasPrefix(filepath.Base(fn), "size.log"), "every rotated file should share the base prefix")
+	}
+
+	assert.NoError(t, os.RemoveAll(dir))
+}
+
+func TestRotationBySizeSurvivesRestart(t *testing.T) {
+	dir, err := os.MkdirTemp("", "file-rotatelogs-size-restart")
+	if !assert.NoError(t, err, `creating temporary directory should succeed`) {
+		return
+	}
+
+	fn := filepath.Join(dir, "size-restart.log")
+	rl, err := New(fn, WithRotationSize(20))
+	if !assert.NoError(t, err, `New should succeed`) {
+		return
+	}
+	_, err = rl.Write([]byte("0123456789"))
+	if !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+	assert.NoError(t, rl.Close())
+
+	// A freshly constructed writer picking up an existing file should
+	// seed its size counter from disk, not assume it's empty.
+	rl, err = New(fn, WithRotationSize(20))
+	if !assert.NoError(t, err, `New should succeed`) {
+		return
+	}
+	_, err = rl.Write([]byte("0123456789"))
+	if !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+	if !assert.True(t, strings.HasSuffix(rl.CurrentFileName(), "size-restart.log"), "first write should still land in the base file") {
+		return
+	}
+
+	_, err = rl.Write([]byte("0123456789"))
+	if !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+	if !assert.True(t, strings.HasSuffix(rl.CurrentFileName(), ".1"), "exceeding the size threshold should rotate to a generational suffix") {
+		return
+	}
+
+	assert.NoError(t, rl.Close())
+	assert.NoError(t, os.RemoveAll(dir))
+}
+
+func TestCompressOnRotate(t *testing.T) {
+	dir, err := os.MkdirTemp("", "file-rotatelogs-compress")
+	if !assert.NoError(t, err, `creating temporary directory should succeed`) {
+		return
+	}
+
+	rl, err := New(
+		filepath.Join(dir, "compress.log"),
+		WithCompress(true),
+	)
+	if !assert.NoError(t, err, `New should succeed`) {
+		return
+	}
+
+	var rotated []string
+	for i := 0; i < 3; i++ {
+		content := fmt.Sprintf("line %d", i)
+		_, err := rl.Write([]byte(content))
+		if !assert.NoError(t, err, "rl.Write should succeed") {
+			return
+		}
+		rotated = append(rotated, rl.CurrentFileName())
+		if !assert.NoError(t, rl.Rotate(), "rl.Rotate should succeed") {
+			return
+		}
+	}
+
+	// CurrentFileName is the most recent rotation and was never gzipped
+	// away from, so it must still be readable as plain text.
+	lastContent, err := os.ReadFile(rl.CurrentFileName())
+	if !assert.NoError(t, err, "reading the active file should succeed") {
+		return
+	}
+	if !assert.Equal(t, "", string(lastContent), "the newly rotated-to file should start out empty") {
+		return
+	}
+
+	assert.NoError(t, rl.Close())
+
+	for i, fn := range rotated {
+		assert.NoFileExists(t, fn, "rotated-away file %s should have been compressed away", fn)
+		gzFn := fn + ".gz"
+		assert.FileExists(t, gzFn, "rotated-away file should exist in .gz form")
+
+		f, err := os.Open(gzFn)
+		if !assert.NoError(t, err, "opening %s should succeed", gzFn) {
+			return
+		}
+		gr, err := gzip.NewReader(f)
+		if !assert.NoError(t, err, "gzip.NewReader(%s) should succeed", gzFn) {
+			f.Close()
+			return
+		}
+		decompressed, err := io.ReadAll(gr)
+		gr.Close()
+		f.Close()
+		if !assert.NoError(t, err, "decompressing %s should succeed", gzFn) {
+			return
+		}
+		assert.Equal(t, fmt.Sprintf("line %d", i), string(decompressed), "decompressed content should match what was written")
+	}
+
+	assert.NoError(t, os.RemoveAll(dir))
+}
+
+func TestCompressFailureEmitsWriteError(t *testing.T) {
+	fs := NewMemFS()
+
+	rl, err := New(
+		"/var/log/app/compress-failure.log",
+		WithFS(fs),
+		WithCompress(true),
+		WithCompressLevel(99), // out of gzip's accepted range, so compressFile fails
+	)
+	if !assert.NoError(t, err, `New should succeed`) {
+		return
+	}
+
+	events := make(chan Event, 16)
+	defer rl.Subscribe(events)()
+
+	if _, err := rl.Write([]byte("Hello, World!")); !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+	if !assert.NoError(t, rl.Rotate(), "rl.Rotate should succeed") {
+		return
+	}
+	assert.NoError(t, rl.Close())
+
+	var gotWriteError bool
+	drain := true
+	for drain {
+		select {
+		case ev := <-events:
+			if _, ok := ev.(WriteError); ok {
+				gotWriteError = true
+			}
+		default:
+			drain = false
+		}
+	}
+	assert.True(t, gotWriteError, "a failed compression should emit a WriteError instead of disappearing silently")
+}
+
+func TestWithMemFS(t *testing.T) {
+	fs := NewMemFS()
+
+	clock := NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	rl, err := New(
+		"/var/log/app/log%Y%m%d%H%M%S",
+		WithFS(fs),
+		WithClock(clock),
+		WithMaxAge(-1),
+		WithRotationCount(2),
+	)
+	if !assert.NoError(t, err, `New should succeed`) {
+		return
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := rl.Write([]byte("Hello, World!")); !assert.NoError(t, err, "rl.Write should succeed") {
+			return
+		}
+		if !assert.NoError(t, rl.Rotate(), "rl.Rotate should succeed") {
+			return
+		}
+	}
+	assert.NoError(t, rl.Close())
+
+	matches, err := fs.Glob("/var/log/app/log*")
+	if !assert.NoError(t, err, "fs.Glob should succeed") {
+		return
+	}
+	assert.Equal(t, 2, len(matches), "only rotationCount files should remain in the in-memory filesystem")
+
+	content, err := func() ([]byte, error) {
+		f, err := fs.Open(rl.CurrentFileName())
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}()
+	if !assert.NoError(t, err, "reading the active in-memory file should succeed") {
+		return
+	}
+	assert.Equal(t, "", string(content), "the newly rotated-to file should start out empty")
+}
+
+func TestSubscribe(t *testing.T) {
+	fs := NewMemFS()
+
+	clock := NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	rl, err := New(
+		"/var/log/app/log%Y%m%d%H%M%S",
+		WithFS(fs),
+		WithClock(clock),
+		WithMaxAge(-1),
+		WithRotationCount(1),
+	)
+	if !assert.NoError(t, err, `New should succeed`) {
+		return
+	}
+
+	events := make(chan Event, 16)
+	unsubscribe := rl.Subscribe(events)
+
+	if _, err := rl.Write([]byte("Hello, World!")); !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+	if !assert.NoError(t, rl.Rotate(), "rl.Rotate should succeed") {
+		return
+	}
+	assert.NoError(t, rl.Close())
+
+	var gotRotated, gotPurged int
+	drain := true
+	for drain {
+		select {
+		case ev := <-events:
+			switch ev.(type) {
+			case FileRotated:
+				gotRotated++
+			case FilePurged:
+				gotPurged++
+			}
+		default:
+			drain = false
+		}
+	}
+
+	assert.Equal(t, 2, gotRotated, "should see one FileRotated for the initial open and one for Rotate")
+	assert.Equal(t, 1, gotPurged, "rotationCount(1) should purge the file that Rotate moved away from")
+
+	unsubscribe()
+	if !assert.NoError(t, rl.Rotate(), "rl.Rotate should succeed") {
+		return
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("unexpected event delivered after unsubscribe: %#v", ev)
+	default:
+	}
+}
+
 type ClockFunc func() time.Time
 
 func (f ClockFunc) Now() time.Time {
@@ -494,14 +795,13 @@ func TestGHIssue23(t *testing.T) {
 }
 
 func TestForceNewFile(t *testing.T) {
-	dir, err := os.MkdirTemp("", "file-rotatelogs-force-new-file")
-	if !assert.NoError(t, err, `creating temporary directory should succeed`) {
-		return
-	}
+	dir := "/var/log/file-rotatelogs-force-new-file"
+	fs := NewMemFS()
 
 	t.Run("Force a new file", func(t *testing.T) {
 		rl, err := New(
 			filepath.Join(dir, "force-new-file.log"),
+			WithFS(fs),
 			ForceNewFile(),
 		)
 		if !assert.NoError(t, err, "New should succeed") {
@@ -514,6 +814,7 @@ func TestForceNewFile(t *testing.T) {
 			baseFn := filepath.Join(dir, "force-new-file.log")
 			rl, err := New(
 				baseFn,
+				WithFS(fs),
 				ForceNewFile(),
 			)
 			if !assert.NoError(t, err, "New should succeed") {
@@ -529,9 +830,9 @@ func TestForceNewFile(t *testing.T) {
 			if !assert.True(t, suffix == expectedSuffix, "expected suffix %s found %s", expectedSuffix, suffix) {
 				return
 			}
-			assert.FileExists(t, rl.CurrentFileName(), "file does not exist %s", rl.CurrentFileName())
-			content, err := os.ReadFile(rl.CurrentFileName())
-			if !assert.NoError(t, err, "os.ReadFile %s should succeed", rl.CurrentFileName()) {
+			assert.True(t, fileExists(fs, rl.CurrentFileName()), "file does not exist %s", rl.CurrentFileName())
+			content, err := readAll(fs, rl.CurrentFileName())
+			if !assert.NoError(t, err, "readAll %s should succeed", rl.CurrentFileName()) {
 				return
 			}
 			str := fmt.Sprintf("Hello, World%d", i)
@@ -539,9 +840,9 @@ func TestForceNewFile(t *testing.T) {
 				return
 			}
 
-			assert.FileExists(t, baseFn, "file does not exist %s", baseFn)
-			content, err = os.ReadFile(baseFn)
-			if !assert.NoError(t, err, "os.ReadFile should succeed") {
+			assert.True(t, fileExists(fs, baseFn), "file does not exist %s", baseFn)
+			content, err = readAll(fs, baseFn)
+			if !assert.NoError(t, err, "readAll should succeed") {
 				return
 			}
 			if !assert.Equal(t, "Hello, World!", string(content), "read %s from file %s, not expected Hello, World!", string(content), baseFn) {
@@ -554,6 +855,7 @@ func TestForceNewFile(t *testing.T) {
 		baseFn := filepath.Join(dir, "force-new-file-rotate.log")
 		rl, err := New(
 			baseFn,
+			WithFS(fs),
 			ForceNewFile(),
 		)
 		if !assert.NoError(t, err, "New should succeed") {
@@ -567,9 +869,9 @@ func TestForceNewFile(t *testing.T) {
 			}
 			_, _ = rl.Write([]byte("Hello, World"))
 			_, _ = rl.Write([]byte(fmt.Sprintf("%d", i)))
-			assert.FileExists(t, rl.CurrentFileName(), "file does not exist %s", rl.CurrentFileName())
-			content, err := os.ReadFile(rl.CurrentFileName())
-			if !assert.NoError(t, err, "os.ReadFile %s should succeed", rl.CurrentFileName()) {
+			assert.True(t, fileExists(fs, rl.CurrentFileName()), "file does not exist %s", rl.CurrentFileName())
+			content, err := readAll(fs, rl.CurrentFileName())
+			if !assert.NoError(t, err, "readAll %s should succeed", rl.CurrentFileName()) {
 				return
 			}
 			str := fmt.Sprintf("Hello, World%d", i)
@@ -577,9 +879,9 @@ func TestForceNewFile(t *testing.T) {
 				return
 			}
 
-			assert.FileExists(t, baseFn, "file does not exist %s", baseFn)
-			content, err = os.ReadFile(baseFn)
-			if !assert.NoError(t, err, "os.ReadFile should succeed") {
+			assert.True(t, fileExists(fs, baseFn), "file does not exist %s", baseFn)
+			content, err = readAll(fs, baseFn)
+			if !assert.NoError(t, err, "readAll should succeed") {
 				return
 			}
 			if !assert.Equal(t, "Hello, World!", string(content), "read %s from file %s, not expected Hello, World!", string(content), baseFn) {
@@ -587,6 +889,324 @@ func TestForceNewFile(t *testing.T) {
 			}
 		}
 	})
+}
 
-	assert.NoError(t, os.RemoveAll(dir))
+func TestAsyncWrite(t *testing.T) {
+	fs := NewMemFS()
+	rl, err := New(
+		"/var/log/app/async.log",
+		WithFS(fs),
+		WithAsync(1024),
+		WithAsyncOverflowPolicy(AsyncOverflowBlock(0)),
+	)
+	if !assert.NoError(t, err, "New should succeed") {
+		return
+	}
+
+	const goroutines = 20
+	const linesEach = 50
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < linesEach; i++ {
+				line := fmt.Sprintf("g%02d-%04d\n", g, i)
+				if _, err := rl.Write([]byte(line)); err != nil {
+					t.Errorf("rl.Write should succeed: %s", err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if !assert.NoError(t, rl.Flush(), "rl.Flush should succeed") {
+		return
+	}
+	assert.NoError(t, rl.Close())
+
+	content, err := func() ([]byte, error) {
+		f, err := fs.Open(rl.CurrentFileName())
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}()
+	if !assert.NoError(t, err, "reading the in-memory file should succeed") {
+		return
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if !assert.Len(t, lines, goroutines*linesEach, "should see every line written, whole and exactly once") {
+		return
+	}
+
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if !assert.False(t, seen[line], "line %q should not be duplicated or corrupted into another line", line) {
+			return
+		}
+		seen[line] = true
+	}
+	for g := 0; g < goroutines; g++ {
+		for i := 0; i < linesEach; i++ {
+			line := fmt.Sprintf("g%02d-%04d", g, i)
+			assert.True(t, seen[line], "missing line %q", line)
+		}
+	}
+}
+
+func TestAsyncWriteWithRotation(t *testing.T) {
+	fs := NewMemFS()
+	rl, err := New(
+		"/var/log/app/async-rotate.log",
+		WithFS(fs),
+		WithAsync(256),
+		WithAsyncOverflowPolicy(AsyncOverflowBlock(0)),
+	)
+	if !assert.NoError(t, err, "New should succeed") {
+		return
+	}
+
+	const goroutines = 10
+	const linesEach = 30
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < linesEach; i++ {
+				line := fmt.Sprintf("g%02d-%04d\n", g, i)
+				if _, err := rl.Write([]byte(line)); err != nil {
+					t.Errorf("rl.Write should succeed: %s", err)
+				}
+				if i%10 == 0 {
+					_ = rl.Rotate()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if !assert.NoError(t, rl.Flush(), "rl.Flush should succeed") {
+		return
+	}
+	assert.NoError(t, rl.Close())
+
+	matches, err := fs.Glob("/var/log/app/async-rotate.log*")
+	if !assert.NoError(t, err, "fs.Glob should succeed") {
+		return
+	}
+
+	seen := make(map[string]bool)
+	var totalLines int
+	for _, path := range matches {
+		content, err := func() ([]byte, error) {
+			f, err := fs.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return io.ReadAll(f)
+		}()
+		if !assert.NoError(t, err, "reading %s should succeed", path) {
+			return
+		}
+		if len(content) == 0 {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+			if !assert.False(t, seen[line], "line %q should not be duplicated or corrupted across rotated files", line) {
+				return
+			}
+			seen[line] = true
+			totalLines++
+		}
+	}
+
+	assert.Equal(t, goroutines*linesEach, totalLines, "every written line should land intact in exactly one rotated file")
+}
+
+// slowFS wraps an FS and delays every write to simulate a slow
+// underlying disk, so tests can observe whether callers wrongly assume
+// a write completed before it actually lands.
+type slowFS struct {
+	FS
+	delay time.Duration
+}
+
+func (fs *slowFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := fs.FS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &slowFile{File: f, delay: fs.delay}, nil
+}
+
+type slowFile struct {
+	File
+	delay time.Duration
+}
+
+func (f *slowFile) Write(p []byte) (int, error) {
+	time.Sleep(f.delay)
+	return f.File.Write(p)
+}
+
+func TestAsyncFlushWaitsForInFlightWrite(t *testing.T) {
+	fs := &slowFS{FS: NewMemFS(), delay: 50 * time.Millisecond}
+	rl, err := New(
+		"/var/log/app/async-flush.log",
+		WithFS(fs),
+		WithAsync(1024),
+	)
+	if !assert.NoError(t, err, "New should succeed") {
+		return
+	}
+
+	if _, err := rl.Write([]byte("hello")); !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+
+	if !assert.NoError(t, rl.Flush(), "rl.Flush should succeed") {
+		return
+	}
+
+	// Flush must not return until the write dequeued from the async
+	// queue has actually been applied to the file, not merely removed
+	// from the queue.
+	content, err := func() ([]byte, error) {
+		f, err := fs.Open(rl.CurrentFileName())
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}()
+	if !assert.NoError(t, err, "reading the in-memory file should succeed") {
+		return
+	}
+	assert.Equal(t, "hello", string(content), "Flush should block until the in-flight write has landed on disk")
+
+	assert.NoError(t, rl.Close())
+}
+
+// failWriteFS wraps an FS whose opened files always fail to write, to
+// exercise error reporting without actually needing a broken disk.
+type failWriteFS struct {
+	FS
+}
+
+func (fs *failWriteFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := fs.FS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &failWriteFile{File: f}, nil
+}
+
+type failWriteFile struct {
+	File
+}
+
+func (f *failWriteFile) Write(p []byte) (int, error) {
+	return 0, errors.New("simulated write failure")
+}
+
+func TestAsyncWriteErrorNotDuplicated(t *testing.T) {
+	fs := &failWriteFS{FS: NewMemFS()}
+	rl, err := New(
+		"/var/log/app/async-fail.log",
+		WithFS(fs),
+		WithAsync(1024),
+	)
+	if !assert.NoError(t, err, "New should succeed") {
+		return
+	}
+
+	events := make(chan Event, 16)
+	unsubscribe := rl.Subscribe(events)
+	defer unsubscribe()
+
+	if _, err := rl.Write([]byte("hello")); !assert.NoError(t, err, "enqueuing should succeed even though the underlying write will fail") {
+		return
+	}
+	if !assert.NoError(t, rl.Flush(), "rl.Flush should succeed") {
+		return
+	}
+	assert.NoError(t, rl.Close())
+
+	var gotWriteError int
+	drain := true
+	for drain {
+		select {
+		case ev := <-events:
+			if _, ok := ev.(WriteError); ok {
+				gotWriteError++
+			}
+		default:
+			drain = false
+		}
+	}
+	assert.Equal(t, 1, gotWriteError, "a single underlying write failure should be reported exactly once, not once per emitter")
+}
+
+func TestRotationTimeAndSizeSameWrite(t *testing.T) {
+	fs := NewMemFS()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() time.Time { return now })
+
+	rl, err := New(
+		"/var/log/app/log%Y%m%d%H%M%S",
+		WithFS(fs),
+		WithClock(clock),
+		WithRotationTime(time.Second),
+		WithRotationSize(5),
+	)
+	if !assert.NoError(t, err, "New should succeed") {
+		return
+	}
+
+	events := make(chan Event, 16)
+	unsubscribe := rl.Subscribe(events)
+	defer unsubscribe()
+
+	if _, err := rl.Write([]byte("ab")); !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+
+	// Advance past the rotation boundary; the payload below also exceeds
+	// WithRotationSize, so both triggers fire on the same Write call.
+	// Only one rotation should happen.
+	now = now.Add(2 * time.Second)
+	if _, err := rl.Write([]byte("0123456789")); !assert.NoError(t, err, "rl.Write should succeed") {
+		return
+	}
+	assert.NoError(t, rl.Close())
+
+	var gotRotated int
+	drain := true
+	for drain {
+		select {
+		case ev := <-events:
+			if _, ok := ev.(FileRotated); ok {
+				gotRotated++
+			}
+		default:
+			drain = false
+		}
+	}
+	assert.Equal(t, 2, gotRotated, "the second Write should produce exactly one rotation, not one each for time and size")
+
+	matches, err := fs.Glob("/var/log/app/log*")
+	if !assert.NoError(t, err, "fs.Glob should succeed") {
+		return
+	}
+	for _, path := range matches {
+		assert.False(t, strings.Contains(filepath.Base(path), "."), "no generational-suffix file should have been created: %s", path)
+	}
 }
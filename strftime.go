@@ -0,0 +1,52 @@
+package rotatelogs
+
+import (
+	"strings"
+	"time"
+)
+
+// strftimeToLayout translates the small subset of strftime verbs that
+// RotateLogs supports in its filename pattern into the equivalent
+// sequence of time.Time.Format directives. Unrecognized verbs are left
+// untouched so they show up verbatim in the generated filename, which
+// makes mistakes in a pattern obvious rather than silently eaten.
+var strftimeVerbs = []struct {
+	verb   string
+	layout string
+}{
+	{"%Y", "2006"},
+	{"%y", "06"},
+	{"%m", "01"},
+	{"%d", "02"},
+	{"%H", "15"},
+	{"%M", "04"},
+	{"%S", "05"},
+	{"%%", "%"},
+}
+
+// genFilename expands the strftime-style verbs in pattern using t.
+func genFilename(pattern string, t time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i == len(pattern)-1 {
+			b.WriteByte(pattern[i])
+			continue
+		}
+
+		verb := pattern[i : i+2]
+		matched := false
+		for _, v := range strftimeVerbs {
+			if v.verb == verb {
+				b.WriteString(t.Format(v.layout))
+				matched = true
+				break
+			}
+		}
+		if matched {
+			i++
+			continue
+		}
+		b.WriteByte(pattern[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,17 @@
+package rotatelogs
+
+import "strings"
+
+// globPatternFromPattern turns a strftime-style filename pattern into a
+// glob pattern suitable for FS.Glob, by replacing every time verb with
+// a wildcard.
+func globPatternFromPattern(p string) string {
+	g := p
+	for _, v := range strftimeVerbs {
+		if v.verb == "%%" {
+			continue
+		}
+		g = strings.ReplaceAll(g, v.verb, "*")
+	}
+	return g
+}